@@ -1,13 +1,13 @@
 package utils
 
 import (
+	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
 )
 
-var RetryDelay = 1000
-
 func FilterZoneIds(vs []upcloud.Zone, f func(upcloud.Zone) bool) []string {
 	vsf := make([]string, 0)
 	for _, v := range vs {
@@ -61,23 +61,69 @@ func min(x, y int) int {
 	return y
 }
 
-// WithRetry attempts to call the provided function until it has been successfully called or the number of calls exceeds retries delaying the consecutive calls by given delay
-func WithRetry(fn func() (interface{}, error), retries int, delay time.Duration) (interface{}, error) {
-	var err error
-	var res interface{}
-	for count := 0; true; count++ {
-		if delay > 0 {
-			time.Sleep(delay)
+// RetryPolicy configures the exponential backoff used by WithRetry.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times fn will be called before giving up.
+	MaxAttempts int
+	// InitialDelay is the base delay before the second attempt.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts, regardless of Multiplier.
+	MaxDelay time.Duration
+	// Multiplier is applied to the delay after every failed attempt.
+	Multiplier float64
+	// Retryable decides whether a given error should be retried. Callers wrapping a
+	// non-idempotent mutation should set this to avoid retrying (and duplicating) a
+	// call that may have already succeeded server-side on an error that merely looks
+	// transient. A nil Retryable retries on any error.
+	Retryable func(error) bool
+}
+
+// RetryError is returned by WithRetry once MaxAttempts is exhausted, or as soon as
+// Retryable rejects an error. It wraps the last error returned by fn and records how
+// many attempts were made.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempts, last error: %s", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// WithRetry calls fn until it succeeds or policy.MaxAttempts is reached, delaying
+// consecutive attempts by an exponentially increasing, fully-jittered backoff:
+// sleep = random(0, min(MaxDelay, InitialDelay * Multiplier^(attempt-1))), where attempt
+// is the 1-based number of the attempt that just failed. This spreads
+// out retries from many concurrent callers instead of having them retry in lockstep.
+func WithRetry(fn func() (interface{}, error), policy RetryPolicy) (interface{}, error) {
+	var lastErr error
+	delay := policy.InitialDelay
+	attempt := 1
+
+	for ; attempt <= policy.MaxAttempts; attempt++ {
+		res, err := fn()
+		if err == nil {
+			return res, nil
 		}
-		if count >= retries {
+		lastErr = err
+
+		if attempt == policy.MaxAttempts || (policy.Retryable != nil && !policy.Retryable(err)) {
 			break
 		}
-		res, err = fn()
-		if err == nil {
-			return res, nil
-		} else {
-			continue
+
+		sleep := delay
+		if policy.MaxDelay > 0 && sleep > policy.MaxDelay {
+			sleep = policy.MaxDelay
 		}
+		if sleep > 0 {
+			time.Sleep(time.Duration(rand.Int63n(int64(sleep) + 1)))
+		}
+		delay = time.Duration(float64(delay) * policy.Multiplier)
 	}
-	return nil, err
+
+	return nil, &RetryError{Attempts: attempt, Err: lastErr}
 }
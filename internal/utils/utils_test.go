@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRetrySucceedsAfterTransientErrors(t *testing.T) {
+	attempts := 0
+	res, err := WithRetry(func() (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("transient error")
+		}
+		return "ok", nil
+	}, RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 2})
+
+	if err != nil {
+		t.Fatalf("WithRetry() returned error: %v", err)
+	}
+	if res != "ok" {
+		t.Fatalf("expected result %q, got %q", "ok", res)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	failure := errors.New("persistent error")
+
+	_, err := WithRetry(func() (interface{}, error) {
+		attempts++
+		return nil, failure
+	}, RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 2})
+
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a *RetryError, got %T: %v", err, err)
+	}
+	if retryErr.Attempts != 3 {
+		t.Fatalf("expected RetryError.Attempts to be 3, got %d", retryErr.Attempts)
+	}
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected RetryError to wrap the underlying failure")
+	}
+}
+
+func TestWithRetryStopsImmediatelyOnNonRetryableError(t *testing.T) {
+	attempts := 0
+	failure := errors.New("not found")
+
+	_, err := WithRetry(func() (interface{}, error) {
+		attempts++
+		return nil, failure
+	}, RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Multiplier:   2,
+		Retryable:    func(error) bool { return false },
+	})
+
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a *RetryError, got %T: %v", err, err)
+	}
+	if retryErr.Attempts != 1 {
+		t.Fatalf("expected RetryError.Attempts to be 1, got %d", retryErr.Attempts)
+	}
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected RetryError to wrap the underlying failure")
+	}
+}
@@ -0,0 +1,165 @@
+package upcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUpCloudFloatingIPAssociation() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Attaches a floating IP address to a running server's network interface. Unlike a `network_interface` block, the association can be changed without rebuilding the server, which makes it suitable for rotating public IPs between servers.",
+		CreateContext: resourceUpCloudFloatingIPAssociationCreate,
+		ReadContext:   resourceUpCloudFloatingIPAssociationRead,
+		DeleteContext: resourceUpCloudFloatingIPAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUpCloudFloatingIPAssociationImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"ip_address": {
+				Description: "The floating IP address to attach",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"server_id": {
+				Description: "The unique identifier of the server the address should be attached to",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"mac_address": {
+				Description: "The MAC address of the network interface to attach the address to. Defaults to the server's first public interface when omitted, which matters when the target server has more than one network interface.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceUpCloudFloatingIPAssociationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	mac := d.Get("mac_address").(string)
+	if mac == "" {
+		resolved, err := firstPublicInterfaceMAC(client, d.Get("server_id").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		mac = resolved
+	}
+
+	if _, err := withAPIRetry(func() (interface{}, error) {
+		return client.ModifyIPAddress(&request.ModifyIPAddressRequest{
+			IPAddress: d.Get("ip_address").(string),
+			MAC:       mac,
+		})
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("ip_address").(string), d.Get("server_id").(string)))
+	log.Printf("[INFO] Floating IP %s attached to server %s", d.Get("ip_address"), d.Get("server_id"))
+
+	return resourceUpCloudFloatingIPAssociationRead(ctx, d, meta)
+}
+
+func resourceUpCloudFloatingIPAssociationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	res, err := withAPIRetry(func() (interface{}, error) {
+		return client.GetIPAddressDetails(&request.GetIPAddressDetailsRequest{
+			Address: d.Get("ip_address").(string),
+		})
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ipAddress := res.(*upcloud.IPAddress)
+
+	serverRes, err := withAPIRetry(func() (interface{}, error) {
+		return client.GetServerDetails(&request.GetServerDetailsRequest{UUID: d.Get("server_id").(string)})
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	server := serverRes.(*upcloud.ServerDetails)
+
+	attached := false
+	for _, iface := range server.Networking.Interfaces {
+		if iface.MAC == ipAddress.MAC {
+			attached = true
+			d.Set("mac_address", iface.MAC)
+			break
+		}
+	}
+
+	if !attached {
+		// the floating IP was detached outside of Terraform
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceUpCloudFloatingIPAssociationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	// This resource only ever reassigns a floating IP that is presumed to already exist
+	// (it never creates one); destroying it must detach the address from server_id, not
+	// release it back to UpCloud's pool, since the address is likely still owned and used
+	// elsewhere. Clearing the MAC detaches without releasing, which also lets ForceNew
+	// replacements (e.g. a changed server_id) reattach the same address in Create instead
+	// of operating on one that was just deallocated.
+	log.Printf("[INFO] Detaching floating IP %s from server %s", d.Get("ip_address"), d.Get("server_id"))
+	if _, err := withAPIRetry(func() (interface{}, error) {
+		return client.ModifyIPAddress(&request.ModifyIPAddressRequest{
+			IPAddress: d.Get("ip_address").(string),
+			MAC:       "",
+		})
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceUpCloudFloatingIPAssociationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid import id %q, expected format <ip_address>/<server_uuid>", d.Id())
+	}
+
+	d.Set("ip_address", parts[0])
+	d.Set("server_id", parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func firstPublicInterfaceMAC(client *service.Service, serverUUID string) (string, error) {
+	res, err := withAPIRetry(func() (interface{}, error) {
+		return client.GetServerDetails(&request.GetServerDetailsRequest{UUID: serverUUID})
+	})
+	if err != nil {
+		return "", err
+	}
+	server := res.(*upcloud.ServerDetails)
+
+	for _, iface := range server.Networking.Interfaces {
+		if iface.Type == upcloud.NetworkTypePublic {
+			return iface.MAC, nil
+		}
+	}
+
+	return "", fmt.Errorf("server %s has no public network interface to attach the floating IP to", serverUUID)
+}
@@ -0,0 +1,56 @@
+package upcloud
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"testing"
+)
+
+func TestAccUpCloudFloatingIPAssociation_basic(t *testing.T) {
+	var providers []*schema.Provider
+
+	resourceName := "upcloud_floating_ip_association.my_association"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUpCloudFloatingIPAssociationConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "server_id", "upcloud_server.my_server", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "mac_address"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUpCloudFloatingIPAssociationConfig_basic() string {
+	return `
+resource "upcloud_server" "my_server" {
+	hostname = "tf-test-server.example.com"
+	zone     = "fi-hel1"
+	plan     = "1xCPU-1GB"
+
+	network_interface {
+		type = "public"
+	}
+
+	template {
+		storage = "Ubuntu Server 22.04 LTS (Jammy Jellyfish)"
+		size    = 10
+	}
+
+	login {
+		user            = "tfacc"
+		create_password = true
+	}
+}
+
+resource "upcloud_floating_ip_association" "my_association" {
+	ip_address = "10.0.0.100"
+	server_id  = upcloud_server.my_server.id
+}
+`
+}
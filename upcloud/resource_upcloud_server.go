@@ -2,6 +2,7 @@ package upcloud
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -26,6 +27,14 @@ func resourceUpCloudServer() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceUpCloudServerResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceUpCloudServerStateUpgradeV0,
+				Version: 0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"hostname": {
 				Description:  "A valid domain name",
@@ -167,6 +176,123 @@ func resourceUpCloudServer() *schema.Resource {
 				Optional:    true,
 				ForceNew:    true,
 			},
+			"personality": {
+				Description: "One or more files to inject into the server's filesystem at boot, delivered as part of the cloud-init user data",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Description: "The absolute path the file should be written to",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"content": {
+							Description: "The file contents",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Sensitive:   true,
+						},
+						"content_sha1": {
+							Description: "SHA1 hash of `content`, used so changes to secret file bodies still show up in a plan without the body itself leaking into it",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"cloud_init": {
+				Description: "A structured cloud-init configuration that is merged with `personality` into the server's user data",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"write_files": {
+							Description: "Files cloud-init should write on first boot",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"path": {
+										Description: "The absolute path the file should be written to",
+										Type:        schema.TypeString,
+										Required:    true,
+										ForceNew:    true,
+									},
+									"content": {
+										Description: "The file contents",
+										Type:        schema.TypeString,
+										Required:    true,
+										ForceNew:    true,
+										Sensitive:   true,
+									},
+								},
+							},
+						},
+						"runcmd": {
+							Description: "Commands cloud-init should run on first boot",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"ssh_authorized_keys": {
+							Description: "Public keys cloud-init should authorize for the default user",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"power_state": {
+				Description:  "The power state to maintain for the server: `started` or `stopped`. Stopping a server this way does not destroy it, which is useful for cost-sensitive dev/test environments and planned maintenance windows.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"started", "stopped"}, false),
+			},
+			"scheduler_hints": {
+				Description: "Placement hints for the UpCloud scheduler, such as a server group to join for anti-affinity",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The unique identifier of an `upcloud_server_group` this server should belong to",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"policy": {
+							Description:  "How strictly the group membership should be enforced when placing this server",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "anti-affinity",
+							ValidateFunc: validation.StringInSlice([]string{"anti-affinity", "affinity", "soft-anti-affinity"}, false),
+						},
+						"different_host": {
+							Description: "A list of server UUIDs this server should be placed on a different hypervisor from",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"same_host": {
+							Description: "A list of server UUIDs this server should be placed on the same hypervisor as",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
 			"plan": {
 				Description: "The pricing plan used for the server",
 				Type:        schema.TypeString,
@@ -288,18 +414,49 @@ func resourceUpCloudServer() *schema.Resource {
 func resourceUpCloudServerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*service.Service)
 
+	if err := applyPersonalityHashes(d); err != nil {
+		return diag.FromErr(err)
+	}
+	userData, err := buildUserData(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	r, err := server.BuildServerOpts(d, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	server, err := client.CreateServer(r)
+	// Pass the rendered multipart payload straight to the API rather than writing it
+	// back into the user_data attribute: that payload embeds the base64 of every
+	// personality/cloud_init file, and user_data is not Sensitive, so echoing it there
+	// would leak those contents into plan/show output despite content being marked
+	// Sensitive and hashed into content_sha1 for exactly that reason.
+	r.UserData = userData
+	res, err := withAPIRetry(func() (interface{}, error) { return client.CreateServer(r) })
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	server := res.(*upcloud.ServerDetails)
 
 	d.SetId(server.UUID)
 	log.Printf("[INFO] Server %s with UUID %s created", server.Title, server.UUID)
 
+	// CreateServer has no field for group membership (only the dedicated
+	// AddServerToServerGroup/RemoveServerFromServerGroup pair), so it can't be folded
+	// into the create request itself. Join the group here, before waiting for the
+	// server to reach its started state, so the scheduler still has the placement hint
+	// available while the server is being placed on a hypervisor rather than after.
+	if group, ok := d.GetOk("scheduler_hints.0.group"); ok {
+		if _, err := withAPIRetry(func() (interface{}, error) {
+			return client.AddServerToServerGroup(schedulerHintsAddRequest(d, group.(string), server.UUID))
+		}); err != nil {
+			if !isSoftSchedulerHintsPolicy(d) || !isServerGroupPlacementConflict(err) {
+				return diag.FromErr(err)
+			}
+			log.Printf("[WARN] could not satisfy soft-anti-affinity scheduler hint for server %s: %s", server.UUID, err)
+		}
+	}
+
 	server, err = client.WaitForServerState(&request.WaitForServerStateRequest{
 		UUID:         server.UUID,
 		DesiredState: upcloud.ServerStateStarted,
@@ -318,6 +475,21 @@ func resourceUpCloudServerCreate(ctx context.Context, d *schema.ResourceData, me
 		return diag.FromErr(err)
 	}
 
+	if d.Get("power_state").(string) == "stopped" {
+		if _, err := withAPIRetry(func() (interface{}, error) {
+			return client.StopServer(&request.StopServerRequest{UUID: server.UUID})
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+		if _, err := client.WaitForServerState(&request.WaitForServerStateRequest{
+			UUID:         server.UUID,
+			DesiredState: upcloud.ServerStateStopped,
+			Timeout:      time.Minute * 5,
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceUpCloudServerRead(ctx, d, meta)
 }
 
@@ -329,15 +501,22 @@ func resourceUpCloudServerRead(ctx context.Context, d *schema.ResourceData, meta
 	r := &request.GetServerDetailsRequest{
 		UUID: d.Id(),
 	}
-	server, err := client.GetServerDetails(r)
+	res, err := withAPIRetry(func() (interface{}, error) { return client.GetServerDetails(r) })
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	server := res.(*upcloud.ServerDetails)
 	d.Set("hostname", server.Hostname)
 	d.Set("title", server.Title)
 	d.Set("zone", server.Zone)
 	d.Set("cpu", server.CoreNumber)
 	d.Set("mem", server.MemoryAmount)
+	switch server.State {
+	case upcloud.ServerStateStarted:
+		d.Set("power_state", "started")
+	case upcloud.ServerStateStopped:
+		d.Set("power_state", "stopped")
+	}
 
 	networkInterfaces := []map[string]interface{}{}
 	var connIP string
@@ -396,6 +575,31 @@ func resourceUpCloudServerRead(ctx context.Context, d *schema.ResourceData, meta
 	}
 	d.Set("storage_devices", storageDevices)
 
+	if group, ok := d.GetOk("scheduler_hints.0.group"); ok {
+		res, err := withAPIRetry(func() (interface{}, error) {
+			return client.GetServerGroup(&request.GetServerGroupRequest{UUID: group.(string)})
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		serverGroup := res.(*upcloud.ServerGroup)
+
+		stillMember := false
+		for _, member := range serverGroup.Servers {
+			if member == d.Id() {
+				stillMember = true
+				break
+			}
+		}
+		if !stillMember {
+			// the server was removed from the group outside of Terraform; clear the
+			// attribute so a plan surfaces the drift instead of silently re-attaching it.
+			hints := d.Get("scheduler_hints.0").(map[string]interface{})
+			hints["group"] = ""
+			d.Set("scheduler_hints", []map[string]interface{}{hints})
+		}
+	}
+
 	// Initialize the connection information.
 	d.SetConnInfo(map[string]string{
 		"host":     connIP,
@@ -410,12 +614,13 @@ func resourceUpCloudServerRead(ctx context.Context, d *schema.ResourceData, meta
 func resourceUpCloudServerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*service.Service)
 
-	serverDetails, err := client.GetServerDetails(&request.GetServerDetailsRequest{
-		UUID: d.Id(),
+	res, err := withAPIRetry(func() (interface{}, error) {
+		return client.GetServerDetails(&request.GetServerDetailsRequest{UUID: d.Id()})
 	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	serverDetails := res.(*upcloud.ServerDetails)
 	if err := server.VerifyServerStopped(d.Id(), meta); err != nil {
 		return diag.FromErr(err)
 	}
@@ -438,18 +643,20 @@ func resourceUpCloudServerUpdate(ctx context.Context, d *schema.ResourceData, me
 	}
 	r.Hostname = d.Get("hostname").(string)
 
-	if _, err := client.ModifyServer(r); err != nil {
+	if _, err := withAPIRetry(func() (interface{}, error) { return client.ModifyServer(r) }); err != nil {
 		return diag.FromErr(err)
 	}
 
 	// handle the template
 	if d.HasChanges("template.0.title", "template.0.size", "template.0.backup_rule") {
 		template := d.Get("template.0").(map[string]interface{})
-		if _, err := client.ModifyStorage(&request.ModifyStorageRequest{
-			UUID:       template["id"].(string),
-			Size:       template["size"].(int),
-			Title:      template["title"].(string),
-			BackupRule: storage.BackupRule(d.Get("template.0.backup_rule.0").(map[string]interface{})),
+		if _, err := withAPIRetry(func() (interface{}, error) {
+			return client.ModifyStorage(&request.ModifyStorageRequest{
+				UUID:       template["id"].(string),
+				Size:       template["size"].(int),
+				Title:      template["title"].(string),
+				BackupRule: storage.BackupRule(d.Get("template.0.backup_rule.0").(map[string]interface{})),
+			})
 		}); err != nil {
 			return diag.FromErr(err)
 		}
@@ -457,16 +664,20 @@ func resourceUpCloudServerUpdate(ctx context.Context, d *schema.ResourceData, me
 	// should reattach if address changed
 	if d.HasChange("template.0.address") {
 		o, n := d.GetChange("template.0.address")
-		if _, err := client.DetachStorage(&request.DetachStorageRequest{
-			ServerUUID: d.Id(),
-			Address:    o.(string),
+		if _, err := withAPIRetry(func() (interface{}, error) {
+			return client.DetachStorage(&request.DetachStorageRequest{
+				ServerUUID: d.Id(),
+				Address:    o.(string),
+			})
 		}); err != nil {
 			return diag.FromErr(err)
 		}
-		if _, err := client.AttachStorage(&request.AttachStorageRequest{
-			Address:     n.(string),
-			ServerUUID:  d.Id(),
-			StorageUUID: d.Get("template.0.id").(string),
+		if _, err := withAPIRetry(func() (interface{}, error) {
+			return client.AttachStorage(&request.AttachStorageRequest{
+				Address:     n.(string),
+				ServerUUID:  d.Id(),
+				StorageUUID: d.Get("template.0.id").(string),
+			})
 		}); err != nil {
 			return diag.FromErr(err)
 		}
@@ -481,9 +692,12 @@ func resourceUpCloudServerUpdate(ctx context.Context, d *schema.ResourceData, me
 			if serverDetails.StorageDevice(storageDevice.(map[string]interface{})["storage"].(string)) == nil {
 				continue
 			}
-			if _, err := client.DetachStorage(&request.DetachStorageRequest{
-				ServerUUID: d.Id(),
-				Address:    storageDevice.(map[string]interface{})["address"].(string),
+			storageDevice := storageDevice.(map[string]interface{})
+			if _, err := withAPIRetry(func() (interface{}, error) {
+				return client.DetachStorage(&request.DetachStorageRequest{
+					ServerUUID: d.Id(),
+					Address:    storageDevice["address"].(string),
+				})
 			}); err != nil {
 				return diag.FromErr(err)
 			}
@@ -491,17 +705,48 @@ func resourceUpCloudServerUpdate(ctx context.Context, d *schema.ResourceData, me
 		// attach the storages that are new or have changed
 		for _, storageDevice := range n.(*schema.Set).Difference(o.(*schema.Set)).List() {
 			storageDevice := storageDevice.(map[string]interface{})
-			if _, err := client.AttachStorage(&request.AttachStorageRequest{
-				ServerUUID:  d.Id(),
-				Address:     storageDevice["address"].(string),
-				StorageUUID: storageDevice["storage"].(string),
-				Type:        storageDevice["type"].(string),
+			if _, err := withAPIRetry(func() (interface{}, error) {
+				return client.AttachStorage(&request.AttachStorageRequest{
+					ServerUUID:  d.Id(),
+					Address:     storageDevice["address"].(string),
+					StorageUUID: storageDevice["storage"].(string),
+					Type:        storageDevice["type"].(string),
+				})
+			}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+	if d.HasChange("scheduler_hints.0.group") {
+		o, n := d.GetChange("scheduler_hints.0.group")
+		if oldGroup := o.(string); oldGroup != "" {
+			if _, err := withAPIRetry(func() (interface{}, error) {
+				return nil, client.RemoveServerFromServerGroup(&request.RemoveServerFromServerGroupRequest{
+					ServerGroupUUID: oldGroup,
+					ServerUUID:      d.Id(),
+				})
 			}); err != nil {
 				return diag.FromErr(err)
 			}
 		}
+		if newGroup := n.(string); newGroup != "" {
+			if _, err := withAPIRetry(func() (interface{}, error) {
+				return client.AddServerToServerGroup(schedulerHintsAddRequest(d, newGroup, d.Id()))
+			}); err != nil {
+				if !isSoftSchedulerHintsPolicy(d) || !isServerGroupPlacementConflict(err) {
+					return diag.FromErr(err)
+				}
+				log.Printf("[WARN] could not satisfy soft-anti-affinity scheduler hint for server %s: %s", d.Id(), err)
+			}
+		}
 	}
-	if err := server.VerifyServerStarted(d.Id(), meta); err != nil {
+
+	if d.Get("power_state").(string) == "stopped" {
+		// the server was already stopped above to apply the changes; leave it that way
+		if err := server.VerifyServerStopped(d.Id(), meta); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if err := server.VerifyServerStarted(d.Id(), meta); err != nil {
 		return diag.FromErr(err)
 	}
 	return resourceUpCloudServerRead(ctx, d, meta)
@@ -521,7 +766,7 @@ func resourceUpCloudServerDelete(ctx context.Context, d *schema.ResourceData, me
 		UUID: d.Id(),
 	}
 	log.Printf("[INFO] Deleting server (server UUID: %s)", d.Id())
-	err := client.DeleteServer(deleteServerRequest)
+	_, err := withAPIRetry(func() (interface{}, error) { return nil, client.DeleteServer(deleteServerRequest) })
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -533,7 +778,7 @@ func resourceUpCloudServerDelete(ctx context.Context, d *schema.ResourceData, me
 			UUID: template["id"].(string),
 		}
 		log.Printf("[INFO] Deleting server storage (storage UUID: %s)", deleteStorageRequest.UUID)
-		err = client.DeleteStorage(deleteStorageRequest)
+		_, err = withAPIRetry(func() (interface{}, error) { return nil, client.DeleteStorage(deleteStorageRequest) })
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -541,3 +786,46 @@ func resourceUpCloudServerDelete(ctx context.Context, d *schema.ResourceData, me
 
 	return diags
 }
+
+// schedulerHintsAddRequest builds the request to join the server group identified by
+// group, carrying the policy and host placement hints declared on d's scheduler_hints
+// block along with it.
+func schedulerHintsAddRequest(d *schema.ResourceData, group, serverUUID string) *request.AddServerToServerGroupRequest {
+	return &request.AddServerToServerGroupRequest{
+		ServerGroupUUID: group,
+		ServerUUID:      serverUUID,
+		Policy:          d.Get("scheduler_hints.0.policy").(string),
+		DifferentHost:   stringListFromSchema(d.Get("scheduler_hints.0.different_host")),
+		SameHost:        stringListFromSchema(d.Get("scheduler_hints.0.same_host")),
+	}
+}
+
+// isSoftSchedulerHintsPolicy reports whether d's scheduler_hints.policy is
+// "soft-anti-affinity", meaning the scheduler should be asked to honor the placement
+// hint on a best-effort basis rather than failing the apply outright when it can't.
+func isSoftSchedulerHintsPolicy(d *schema.ResourceData) bool {
+	return d.Get("scheduler_hints.0.policy").(string) == "soft-anti-affinity"
+}
+
+// serverGroupPlacementConflictErrorCode is the error code UpCloud returns when it
+// cannot find a hypervisor that satisfies a server group's placement policy, as
+// opposed to some other failure (unknown group, auth, ...) that happens to occur
+// while joining one.
+const serverGroupPlacementConflictErrorCode = "SERVER_GROUP_ANTI_AFFINITY_CONFLICT"
+
+// isServerGroupPlacementConflict reports whether err is specifically a placement
+// policy conflict, so that soft-anti-affinity can swallow only that failure and still
+// surface every other error (bad group UUID, permissions, ...) as a hard apply failure.
+func isServerGroupPlacementConflict(err error) bool {
+	var apiErr *upcloud.Error
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == serverGroupPlacementConflictErrorCode
+}
+
+func stringListFromSchema(raw interface{}) []string {
+	items := raw.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		list = append(list, item.(string))
+	}
+	return list
+}
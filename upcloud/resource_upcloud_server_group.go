@@ -0,0 +1,99 @@
+package upcloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceUpCloudServerGroup() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Creates a server group that servers can be placed into via their `scheduler_hints` block. The group itself carries no placement policy; each member's `scheduler_hints.policy` decides whether it is placed apart from (anti-affinity) or together with (affinity) the other members.",
+		CreateContext: resourceUpCloudServerGroupCreate,
+		ReadContext:   resourceUpCloudServerGroupRead,
+		UpdateContext: resourceUpCloudServerGroupUpdate,
+		DeleteContext: resourceUpCloudServerGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"title": {
+				Description:  "A short, informational description for the group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 64),
+			},
+		},
+	}
+}
+
+func resourceUpCloudServerGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	res, err := withAPIRetry(func() (interface{}, error) {
+		return client.CreateServerGroup(&request.CreateServerGroupRequest{
+			Title: d.Get("title").(string),
+		})
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	group := res.(*upcloud.ServerGroup)
+
+	d.SetId(group.UUID)
+	log.Printf("[INFO] Server group %s with UUID %s created", group.Title, group.UUID)
+
+	return resourceUpCloudServerGroupRead(ctx, d, meta)
+}
+
+func resourceUpCloudServerGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	res, err := withAPIRetry(func() (interface{}, error) {
+		return client.GetServerGroup(&request.GetServerGroupRequest{UUID: d.Id()})
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	group := res.(*upcloud.ServerGroup)
+
+	d.Set("title", group.Title)
+
+	return nil
+}
+
+func resourceUpCloudServerGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	if d.HasChange("title") {
+		if _, err := withAPIRetry(func() (interface{}, error) {
+			return client.ModifyServerGroup(&request.ModifyServerGroupRequest{
+				UUID:  d.Id(),
+				Title: d.Get("title").(string),
+			})
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceUpCloudServerGroupRead(ctx, d, meta)
+}
+
+func resourceUpCloudServerGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*service.Service)
+
+	log.Printf("[INFO] Deleting server group (UUID: %s)", d.Id())
+	if _, err := withAPIRetry(func() (interface{}, error) {
+		return nil, client.DeleteServerGroup(&request.DeleteServerGroupRequest{UUID: d.Id()})
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package upcloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"testing"
+)
+
+func TestAccUpCloudServerGroup_basic(t *testing.T) {
+	var providers []*schema.Provider
+
+	resourceName := "upcloud_server_group.my_group"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUpCloudServerGroupConfig_basic("tf-test-group"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "title", "tf-test-group"),
+				),
+			},
+			{
+				Config: testAccUpCloudServerGroupConfig_basic("tf-test-group-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "title", "tf-test-group-renamed"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUpCloudServerGroupConfig_basic(title string) string {
+	return fmt.Sprintf(`
+resource "upcloud_server_group" "my_group" {
+	title = %q
+}
+`, title)
+}
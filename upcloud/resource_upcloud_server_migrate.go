@@ -0,0 +1,414 @@
+package upcloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/UpCloudLtd/terraform-provider-upcloud/internal/storage"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceUpCloudServerResourceV0 is a static, hand-pinned schema used to decode
+// upcloud_server state stored under schema version 0, so StateUpgraders can interpret
+// state files written by older provider versions. It is a superset of the schema that
+// existed before SchemaVersion was introduced: it also declares source_template,
+// personality, cloud_init, power_state and scheduler_hints, and marks user_data
+// Computed; attributes missing from an older state decode as null. It must NOT be built
+// by slicing fields out of the current resourceUpCloudServer(): that schema keeps
+// changing underneath it, so a later edit to a field's definition (a new default, a
+// Computed flip, ...) would silently change what "version 0" means instead of leaving
+// the historical upgrade source fixed.
+func resourceUpCloudServerResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"hostname": {
+				Description:  "A valid domain name",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 128),
+			},
+			"title": {
+				Description: "A short, informational description",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"zone": {
+				Description: "The zone in which the server will be hosted",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"source_template": {
+				Description: "The unique identifier of an `upcloud_server_template`. The current `upcloud_server` schema has no such attribute, and no attribute of a server is inherited from a template.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"firewall": {
+				Description: "Are firewall rules active for the server",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"metadata": {
+				Description: "Is the metadata service active for the server",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"cpu": {
+				Description:   "The number of CPU for the server",
+				Type:          schema.TypeInt,
+				Computed:      true,
+				Optional:      true,
+				ConflictsWith: []string{"plan"},
+			},
+			"mem": {
+				Description:   "The size of memory for the server (in megabytes)",
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"plan"},
+			},
+			"network_interface": {
+				Type:        schema.TypeList,
+				Description: "One or more blocks describing the network interfaces of the server.",
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip_address_family": {
+							Type:        schema.TypeString,
+							Description: "The IP address type of this interface (one of `IPv4` or `IPv6`).",
+							Optional:    true,
+							ForceNew:    true,
+							Default:     upcloud.IPAddressFamilyIPv4,
+							ValidateDiagFunc: func(v interface{}, _ cty.Path) diag.Diagnostics {
+								switch v.(string) {
+								case upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6:
+									return nil
+								default:
+									return diag.Diagnostics{diag.Diagnostic{
+										Severity: diag.Error,
+										Summary:  "'ip_address_family' has incorrect value",
+										Detail: fmt.Sprintf(
+											"'ip_address_family' must be one of %s or %s",
+											upcloud.IPAddressFamilyIPv4,
+											upcloud.IPAddressFamilyIPv6),
+									}}
+								}
+							},
+						},
+						"ip_address": {
+							Type:        schema.TypeString,
+							Description: "The assigned IP address.",
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+						},
+						"ip_address_floating": {
+							Type:        schema.TypeBool,
+							Description: "`true` is a floating IP address is attached.",
+							Computed:    true,
+						},
+						"mac_address": {
+							Type:        schema.TypeString,
+							Description: "The assigned MAC address.",
+							Computed:    true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Network interface type. For private network interfaces, a network must be specified with an existing network id.",
+							Required:    true,
+							ForceNew:    true,
+							ValidateDiagFunc: func(v interface{}, _ cty.Path) diag.Diagnostics {
+								switch v.(string) {
+								case upcloud.NetworkTypePrivate, upcloud.NetworkTypeUtility, upcloud.NetworkTypePublic:
+									return nil
+								default:
+									return diag.Diagnostics{diag.Diagnostic{
+										Severity: diag.Error,
+										Summary:  "'type' has incorrect value",
+										Detail: fmt.Sprintf(
+											"'type' must be one of %s, %s or %s",
+											upcloud.NetworkTypePrivate,
+											upcloud.NetworkTypePublic,
+											upcloud.NetworkTypeUtility),
+									}}
+								}
+							},
+						},
+						"network": {
+							Type:        schema.TypeString,
+							Description: "The unique ID of a network to attach this network to.",
+							ForceNew:    true,
+							Optional:    true,
+							Computed:    true,
+						},
+						"source_ip_filtering": {
+							Type:        schema.TypeBool,
+							Description: "`true` if source IP should be filtered.",
+							ForceNew:    true,
+							Optional:    true,
+							Default:     true,
+						},
+						"bootable": {
+							Type:        schema.TypeBool,
+							Description: "`true` if this interface should be used for network booting.",
+							ForceNew:    true,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
+			"user_data": {
+				Description: "Defines URL for a server setup script, or the script body itself",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"personality": {
+				Description: "One or more files to inject into the server's filesystem at boot, delivered as part of the cloud-init user data",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Description: "The absolute path the file should be written to",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"content": {
+							Description: "The file contents",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Sensitive:   true,
+						},
+						"content_sha1": {
+							Description: "SHA1 hash of `content`, used so changes to secret file bodies still show up in a plan without the body itself leaking into it",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"cloud_init": {
+				Description: "A structured cloud-init configuration that is merged with `personality` into the server's user data",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"write_files": {
+							Description: "Files cloud-init should write on first boot",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"path": {
+										Description: "The absolute path the file should be written to",
+										Type:        schema.TypeString,
+										Required:    true,
+										ForceNew:    true,
+									},
+									"content": {
+										Description: "The file contents",
+										Type:        schema.TypeString,
+										Required:    true,
+										ForceNew:    true,
+										Sensitive:   true,
+									},
+								},
+							},
+						},
+						"runcmd": {
+							Description: "Commands cloud-init should run on first boot",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"ssh_authorized_keys": {
+							Description: "Public keys cloud-init should authorize for the default user",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"power_state": {
+				Description:  "The power state to maintain for the server: `started` or `stopped`. Stopping a server this way does not destroy it, which is useful for cost-sensitive dev/test environments and planned maintenance windows.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"started", "stopped"}, false),
+			},
+			"scheduler_hints": {
+				Description: "Placement hints for the UpCloud scheduler, such as a server group to join for anti-affinity",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The unique identifier of an `upcloud_server_group` this server should belong to",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"policy": {
+							Description:  "How strictly the group membership should be enforced when placing this server",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "anti-affinity",
+							ValidateFunc: validation.StringInSlice([]string{"anti-affinity", "affinity", "soft-anti-affinity"}, false),
+						},
+						"different_host": {
+							Description: "A list of server UUIDs this server should be placed on a different hypervisor from",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"same_host": {
+							Description: "A list of server UUIDs this server should be placed on the same hypervisor as",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"plan": {
+				Description: "The pricing plan used for the server",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"storage_devices": {
+				Description: "A list of storage devices associated with the server",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"storage": {
+							Description: "A valid storage UUID",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"address": {
+							Description: "The device address the storage will be attached to. Specify only the bus name (ide/scsi/virtio) to auto-select next available address from that bus.",
+							Type:        schema.TypeString,
+							Computed:    true,
+							Optional:    true,
+						},
+						"type": {
+							Description:  "The device type the storage will be attached as",
+							Type:         schema.TypeString,
+							Computed:     true,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"disk", "cdrom"}, false),
+						},
+					},
+				},
+			},
+			"template": {
+				Description: "Block describing the preconfigured operating system",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "The unique identifier for the storage",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"address": {
+							Description: "The device address the storage will be attached to. Specify only the bus name (ide/scsi/virtio) to auto-select next available address from that bus.",
+							Type:        schema.TypeString,
+							Computed:    true,
+							Optional:    true,
+						},
+						"size": {
+							Description:  "The size of the storage in gigabytes",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(10, 2048),
+						},
+						"tier": {
+							Description: "The storage tier to use",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"title": {
+							Description:  "A short, informative description",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringLenBetween(0, 64),
+						},
+						"storage": {
+							Description: "A valid storage UUID or template name",
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Required:    true,
+						},
+						"backup_rule": storage.BackupRuleSchema(),
+					},
+				},
+			},
+			"login": {
+				Description: "Configure access credentials to the server",
+				Type:        schema.TypeSet,
+				ForceNew:    true,
+				MaxItems:    1,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Description: "Username to be create to access the server",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"keys": {
+							Description: "A list of ssh keys to access the server",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"create_password": {
+							Description: "Indicates a password should be create to allow access",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"password_delivery": {
+							Description:  "The delivery method for the server’s root password",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "none",
+							ValidateFunc: validation.StringInSlice([]string{"none", "email", "sms"}, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceUpCloudServerStateUpgradeV0 upgrades state from schema version 0 to 1. No
+// field was renamed or restructured when SchemaVersion was introduced, so the raw
+// state is valid as-is; this is a scaffold for the day a future version needs to.
+func resourceUpCloudServerStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	return rawState, nil
+}
@@ -0,0 +1,246 @@
+package upcloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/UpCloudLtd/terraform-provider-upcloud/internal/storage"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceUpCloudServerTemplate() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Describes a reusable server definition that can be used as the base configuration for one or more `upcloud_server` resources. A template never boots a running server itself; it only stores the configuration to be copied. Since a template is plain Terraform state with no backing API object, reference its attributes directly on the consuming `upcloud_server` (e.g. `cpu = upcloud_server_template.example.cpu`) rather than through an indirection the provider resolves itself.",
+		CreateContext: resourceUpCloudServerTemplateCreate,
+		ReadContext:   resourceUpCloudServerTemplateRead,
+		DeleteContext: resourceUpCloudServerTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name_prefix": {
+				Description:  "A prefix used to generate the unique `title` and ID of this template",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 40),
+			},
+			"title": {
+				Description: "The generated, unique title of the template",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"zone": {
+				Description: "The zone in which servers created from this template will be hosted",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"firewall": {
+				Description: "Are firewall rules active for servers created from this template",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"metadata": {
+				Description: "Is the metadata service active for servers created from this template",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"cpu": {
+				Description:   "The number of CPU for servers created from this template",
+				Type:          schema.TypeInt,
+				Computed:      true,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"plan"},
+			},
+			"mem": {
+				Description:   "The size of memory for servers created from this template (in megabytes)",
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"plan"},
+			},
+			"plan": {
+				Description: "The pricing plan used for servers created from this template",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"network_interface": {
+				Type:        schema.TypeList,
+				Description: "One or more blocks describing the network interfaces to attach to servers created from this template.",
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip_address_family": {
+							Type:        schema.TypeString,
+							Description: "The IP address type of this interface (one of `IPv4` or `IPv6`).",
+							Optional:    true,
+							ForceNew:    true,
+							Default:     upcloud.IPAddressFamilyIPv4,
+							ValidateDiagFunc: func(v interface{}, _ cty.Path) diag.Diagnostics {
+								switch v.(string) {
+								case upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6:
+									return nil
+								default:
+									return diag.Diagnostics{diag.Diagnostic{
+										Severity: diag.Error,
+										Summary:  "'ip_address_family' has incorrect value",
+										Detail: fmt.Sprintf(
+											"'ip_address_family' must be one of %s or %s",
+											upcloud.IPAddressFamilyIPv4,
+											upcloud.IPAddressFamilyIPv6),
+									}}
+								}
+							},
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Network interface type. For private network interfaces, a network must be specified with an existing network id.",
+							Required:    true,
+							ForceNew:    true,
+						},
+						"network": {
+							Type:        schema.TypeString,
+							Description: "The unique ID of a network to attach this network to.",
+							ForceNew:    true,
+							Optional:    true,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"storage_devices": {
+				Description: "A list of storage devices to attach to servers created from this template",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"storage": {
+							Description: "A valid storage UUID",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"address": {
+							Description: "The device address the storage will be attached to. Specify only the bus name (ide/scsi/virtio) to auto-select next available address from that bus.",
+							Type:        schema.TypeString,
+							Computed:    true,
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"type": {
+							Description:  "The device type the storage will be attached as",
+							Type:         schema.TypeString,
+							Computed:     true,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringInSlice([]string{"disk", "cdrom"}, false),
+						},
+					},
+				},
+			},
+			"template": {
+				Description: "Block describing the preconfigured operating system to use as the root disk for servers created from this template",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"size": {
+							Description:  "The size of the storage in gigabytes",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IntBetween(10, 2048),
+						},
+						"storage": {
+							Description: "A valid storage UUID or template name",
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Required:    true,
+						},
+						"backup_rule": storage.BackupRuleSchema(),
+					},
+				},
+			},
+			"login": {
+				Description: "Configure access credentials for servers created from this template",
+				Type:        schema.TypeSet,
+				ForceNew:    true,
+				MaxItems:    1,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Description: "Username to be create to access the server",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"keys": {
+							Description: "A list of ssh keys to access the server",
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"create_password": {
+							Description: "Indicates a password should be create to allow access",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     false,
+						},
+						"password_delivery": {
+							Description:  "The delivery method for the server’s root password",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      "none",
+							ValidateFunc: validation.StringInSlice([]string{"none", "email", "sms"}, false),
+						},
+					},
+				},
+			},
+			"user_data": {
+				Description: "Defines URL for a server setup script, or the script body itself, for servers created from this template",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceUpCloudServerTemplateCreate(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	id := resource.PrefixedUniqueId(d.Get("name_prefix").(string) + "-")
+	d.SetId(id)
+	d.Set("title", id)
+
+	return nil
+}
+
+func resourceUpCloudServerTemplateRead(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	// A template has no corresponding object in the UpCloud API to refresh against; its
+	// state is whatever Terraform stored at create time, so there is nothing to read back.
+	return nil
+}
+
+func resourceUpCloudServerTemplateDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
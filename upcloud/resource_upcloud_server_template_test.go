@@ -0,0 +1,42 @@
+package upcloud
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"testing"
+)
+
+func TestAccUpCloudServerTemplate_basic(t *testing.T) {
+	var providers []*schema.Provider
+
+	resourceName := "upcloud_server_template.my_template"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUpCloudServerTemplateConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name_prefix", "tf-test-template-"),
+					resource.TestCheckResourceAttr(resourceName, "zone", "fi-hel1"),
+					resource.TestCheckResourceAttrSet(resourceName, "title"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUpCloudServerTemplateConfig_basic() string {
+	return `
+resource "upcloud_server_template" "my_template" {
+	name_prefix = "tf-test-template-"
+	zone        = "fi-hel1"
+	plan        = "1xCPU-1GB"
+
+	network_interface {
+		type = "public"
+	}
+}
+`
+}
@@ -0,0 +1,166 @@
+package upcloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"testing"
+)
+
+func TestAccUpCloudServer_schedulerHints(t *testing.T) {
+	var providers []*schema.Provider
+
+	resourceName := "upcloud_server.my_server"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUpCloudServerConfig_schedulerHints(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "scheduler_hints.0.policy", "soft-anti-affinity"),
+					resource.TestCheckResourceAttrPair(resourceName, "scheduler_hints.0.group", "upcloud_server_group.my_group", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUpCloudServerConfig_schedulerHints() string {
+	return `
+resource "upcloud_server_group" "my_group" {
+	title = "tf-test-group"
+}
+
+resource "upcloud_server" "my_server" {
+	hostname = "tf-test-server.example.com"
+	zone     = "fi-hel1"
+	plan     = "1xCPU-1GB"
+
+	network_interface {
+		type = "public"
+	}
+
+	scheduler_hints {
+		group  = upcloud_server_group.my_group.id
+		policy = "soft-anti-affinity"
+	}
+
+	template {
+		storage = "Ubuntu Server 22.04 LTS (Jammy Jellyfish)"
+		size    = 10
+	}
+
+	login {
+		user            = "tfacc"
+		create_password = true
+	}
+}
+`
+}
+
+func TestAccUpCloudServer_powerState(t *testing.T) {
+	var providers []*schema.Provider
+
+	resourceName := "upcloud_server.my_server"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUpCloudServerConfig_powerState("stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", "stopped"),
+				),
+			},
+			{
+				Config: testAccUpCloudServerConfig_powerState("started"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", "started"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUpCloudServerConfig_powerState(powerState string) string {
+	return fmt.Sprintf(`
+resource "upcloud_server" "my_server" {
+	hostname = "tf-test-server.example.com"
+	zone     = "fi-hel1"
+	plan     = "1xCPU-1GB"
+
+	network_interface {
+		type = "public"
+	}
+
+	template {
+		storage = "Ubuntu Server 22.04 LTS (Jammy Jellyfish)"
+		size    = 10
+	}
+
+	login {
+		user            = "tfacc"
+		create_password = true
+	}
+
+	power_state = %q
+}
+`, powerState)
+}
+
+func TestAccUpCloudServer_personalityAndCloudInit(t *testing.T) {
+	var providers []*schema.Provider
+
+	resourceName := "upcloud_server.my_server"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUpCloudServerConfig_personalityAndCloudInit(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "personality.0.path", "/etc/motd"),
+					resource.TestCheckResourceAttrSet(resourceName, "personality.0.content_sha1"),
+					resource.TestCheckResourceAttr(resourceName, "cloud_init.0.runcmd.0", "echo hello"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUpCloudServerConfig_personalityAndCloudInit() string {
+	return `
+resource "upcloud_server" "my_server" {
+	hostname = "tf-test-server.example.com"
+	zone     = "fi-hel1"
+	plan     = "1xCPU-1GB"
+
+	network_interface {
+		type = "public"
+	}
+
+	template {
+		storage = "Ubuntu Server 22.04 LTS (Jammy Jellyfish)"
+		size    = 10
+	}
+
+	login {
+		user            = "tfacc"
+		create_password = true
+	}
+
+	personality {
+		path    = "/etc/motd"
+		content = "welcome to tf-test-server"
+	}
+
+	cloud_init {
+		runcmd = ["echo hello"]
+	}
+}
+`
+}
@@ -0,0 +1,53 @@
+package upcloud
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/UpCloudLtd/terraform-provider-upcloud/internal/utils"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// retryableAPIErrorCodes are the UpCloud error codes that indicate a transient,
+// safe-to-retry failure (rate limiting, a momentarily unavailable backend) as opposed
+// to one that would just fail again (bad input, auth, not found, ...).
+var retryableAPIErrorCodes = map[string]bool{
+	"REQUEST_RATE_LIMITED":  true,
+	"SERVICE_UNAVAILABLE":   true,
+	"INTERNAL_SERVER_ERROR": true,
+}
+
+// isRetryableAPIError reports whether err looks transient enough to retry. Retrying a
+// non-idempotent mutation (CreateServer, AttachStorage, ReleaseIPAddress, ...) on any
+// error risks duplicating its side effect if the call actually succeeded server-side
+// but the response was lost in transit, so only network-level timeouts and a known set
+// of 429/5xx API error codes are retried; everything else is surfaced immediately.
+func isRetryableAPIError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	var apiErr *upcloud.Error
+	if errors.As(err, &apiErr) {
+		return retryableAPIErrorCodes[apiErr.ErrorCode]
+	}
+
+	return false
+}
+
+// apiRetryPolicy is the default exponential backoff applied to UpCloud API calls
+// that can transiently fail with 429/5xx responses.
+var apiRetryPolicy = utils.RetryPolicy{
+	MaxAttempts:  5,
+	InitialDelay: 500 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+	Multiplier:   2,
+	Retryable:    isRetryableAPIError,
+}
+
+// withAPIRetry applies apiRetryPolicy to a single UpCloud API call.
+func withAPIRetry(fn func() (interface{}, error)) (interface{}, error) {
+	return utils.WithRetry(fn, apiRetryPolicy)
+}
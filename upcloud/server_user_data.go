@@ -0,0 +1,126 @@
+package upcloud
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// buildUserData renders the final user_data payload for a server, combining the
+// plain user_data string with any personality files and cloud_init configuration
+// into a single multipart MIME cloud-init payload. When neither personality nor
+// cloud_init is configured, the plain user_data string is returned unchanged.
+func buildUserData(d *schema.ResourceData) (string, error) {
+	personalities := d.Get("personality").([]interface{})
+	cloudInit, hasCloudInit := d.GetOk("cloud_init.0")
+
+	if len(personalities) == 0 && !hasCloudInit {
+		return d.Get("user_data").(string), nil
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	if hasCloudInit {
+		if err := writeCloudConfigPart(mw, cloudInit.(map[string]interface{})); err != nil {
+			return "", err
+		}
+	}
+
+	if userData := d.Get("user_data").(string); userData != "" {
+		if err := writePart(mw, "text/x-shellscript", "user_data.sh", []byte(userData)); err != nil {
+			return "", err
+		}
+	}
+
+	for _, p := range personalities {
+		p := p.(map[string]interface{})
+		if err := writePart(mw, "text/plain", p["path"].(string), []byte(p["content"].(string))); err != nil {
+			return "", err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return "", fmt.Errorf("failed to close cloud-init multipart payload: %w", err)
+	}
+
+	return fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n%s", mw.Boundary(), buf.String()), nil
+}
+
+func writeCloudConfigPart(mw *multipart.Writer, cloudInit map[string]interface{}) error {
+	cfg := map[string]interface{}{}
+
+	if writeFiles, ok := cloudInit["write_files"].([]interface{}); ok && len(writeFiles) > 0 {
+		files := make([]map[string]interface{}, 0, len(writeFiles))
+		for _, wf := range writeFiles {
+			wf := wf.(map[string]interface{})
+			files = append(files, map[string]interface{}{
+				"path":        wf["path"],
+				"content":     base64.StdEncoding.EncodeToString([]byte(wf["content"].(string))),
+				"encoding":    "b64",
+				"permissions": "0644",
+			})
+		}
+		cfg["write_files"] = files
+	}
+	if runcmd, ok := cloudInit["runcmd"].([]interface{}); ok && len(runcmd) > 0 {
+		cfg["runcmd"] = runcmd
+	}
+	if keys, ok := cloudInit["ssh_authorized_keys"].([]interface{}); ok && len(keys) > 0 {
+		cfg["ssh_authorized_keys"] = keys
+	}
+
+	if len(cfg) == 0 {
+		return nil
+	}
+
+	body, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cloud_init config: %w", err)
+	}
+
+	return writePart(mw, "text/cloud-config", "cloud-config.yaml", append([]byte("#cloud-config\n"), body...))
+}
+
+func writePart(mw *multipart.Writer, contentType, filename string, content []byte) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf(`attachment; filename="%s"`, filename)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write([]byte(base64.StdEncoding.EncodeToString(content)))
+	return err
+}
+
+// applyPersonalityHashes records the SHA1 of each personality file's content in
+// content_sha1 so that a diff shows the file changed without ever printing its body.
+func applyPersonalityHashes(d *schema.ResourceData) error {
+	personalities, ok := d.Get("personality").([]interface{})
+	if !ok || len(personalities) == 0 {
+		return nil
+	}
+
+	hashed := make([]map[string]interface{}, 0, len(personalities))
+	for _, p := range personalities {
+		p := p.(map[string]interface{})
+		sum := sha1.Sum([]byte(p["content"].(string)))
+		hashed = append(hashed, map[string]interface{}{
+			"path":         p["path"],
+			"content":      p["content"],
+			"content_sha1": hex.EncodeToString(sum[:]),
+		})
+	}
+
+	return d.Set("personality", hashed)
+}
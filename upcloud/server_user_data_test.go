@@ -0,0 +1,44 @@
+package upcloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestBuildUserDataPassthroughWithoutPersonalityOrCloudInit(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUpCloudServer().Schema, map[string]interface{}{
+		"user_data": "#!/bin/sh\necho hello\n",
+	})
+
+	got, err := buildUserData(d)
+	if err != nil {
+		t.Fatalf("buildUserData() returned error: %v", err)
+	}
+	if got != "#!/bin/sh\necho hello\n" {
+		t.Fatalf("expected plain user_data to pass through unchanged, got %q", got)
+	}
+}
+
+func TestBuildUserDataBuildsMultipartPayload(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUpCloudServer().Schema, map[string]interface{}{
+		"personality": []interface{}{
+			map[string]interface{}{
+				"path":    "/etc/motd",
+				"content": "welcome",
+			},
+		},
+	})
+
+	got, err := buildUserData(d)
+	if err != nil {
+		t.Fatalf("buildUserData() returned error: %v", err)
+	}
+	if !strings.HasPrefix(got, "Content-Type: multipart/mixed;") {
+		t.Fatalf("expected a multipart/mixed payload, got %q", got)
+	}
+	if !strings.Contains(got, `filename="/etc/motd"`) {
+		t.Fatalf("expected the personality file to be attached, got %q", got)
+	}
+}